@@ -0,0 +1,139 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"fmt"
+)
+
+// GceRef contains s reference to some entity in GCE/GKE world.
+type GceRef struct {
+	Project string
+	Zone    string
+	// Region is set instead of Zone for Migs backed by a regional,
+	// rather than zonal, Instance Group Manager.
+	Region string
+	Name   string
+}
+
+// IsRegional returns true if the ref points at a regional Instance Group Manager.
+func (ref GceRef) IsRegional() bool {
+	return ref.Region != ""
+}
+
+// Mig implements a GCE Managed Instance Group, and is used as a cloud
+// provider node group by the autoscaler.
+type Mig struct {
+	GceRef
+
+	gceManager *GceManager
+
+	minSize int
+	maxSize int
+
+	exist           bool
+	autoprovisioned bool
+
+	// nodePoolName is the name of the GKE node pool backing this mig. It is
+	// only set for migs discovered or created through the GKE node pool APIs.
+	nodePoolName string
+}
+
+// Id returns mig id.
+func (mig *Mig) Id() string {
+	if mig.IsRegional() {
+		return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/regions/%s/instanceGroupManagers/%s",
+			mig.Project, mig.Region, mig.Name)
+	}
+	return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s/instanceGroupManagers/%s",
+		mig.Project, mig.Zone, mig.Name)
+}
+
+// MaxSize returns maximum size of the node group.
+func (mig *Mig) MaxSize() int {
+	return mig.maxSize
+}
+
+// MinSize returns minimum size of the node group.
+func (mig *Mig) MinSize() int {
+	return mig.minSize
+}
+
+// TargetSize returns the current target size of the node group.
+func (mig *Mig) TargetSize() (int64, error) {
+	return mig.gceManager.GetMigSize(mig)
+}
+
+// IncreaseSize increases the size of the node group by delta.
+func (mig *Mig) IncreaseSize(delta int64) error {
+	if delta <= 0 {
+		return fmt.Errorf("size increase must be positive")
+	}
+	size, err := mig.gceManager.GetMigSize(mig)
+	if err != nil {
+		return err
+	}
+	if int(size)+int(delta) > mig.MaxSize() {
+		return fmt.Errorf("size increase too large, desired: %d max: %d", int(size)+int(delta), mig.MaxSize())
+	}
+	return mig.gceManager.SetMigSize(mig, size+delta)
+}
+
+// DecreaseTargetSize decreases the target size of the node group by delta.
+func (mig *Mig) DecreaseTargetSize(delta int64) error {
+	if delta >= 0 {
+		return fmt.Errorf("size decrease must be negative")
+	}
+	size, err := mig.gceManager.GetMigSize(mig)
+	if err != nil {
+		return err
+	}
+	nodes, err := mig.gceManager.GetMigNodes(mig)
+	if err != nil {
+		return err
+	}
+	if int(size)+int(delta) < len(nodes) {
+		return fmt.Errorf("attempt to delete existing nodes targetSize: %d delta: %d existingNodes: %d",
+			size, delta, len(nodes))
+	}
+	return mig.gceManager.SetMigSize(mig, size+delta)
+}
+
+// Exist checks if the node group really exists in the cloud provider.
+func (mig *Mig) Exist() bool {
+	return mig.exist
+}
+
+// Autoprovisioned returns true if the node group is autoprovisioned.
+func (mig *Mig) Autoprovisioned() bool {
+	return mig.autoprovisioned
+}
+
+// Nodes returns a list of instances belonging to this node group.
+func (mig *Mig) Nodes() ([]string, error) {
+	return mig.gceManager.GetMigNodes(mig)
+}
+
+// DeleteNodes deletes the given instances, all of which must belong to this node group.
+func (mig *Mig) DeleteNodes(instances []*GceRef) error {
+	return mig.gceManager.DeleteInstances(instances)
+}
+
+// Debug returns a debug string for the node group.
+func (mig *Mig) Debug() string {
+	return fmt.Sprintf("%s (%d:%d)", mig.Id(), mig.MinSize(), mig.MaxSize())
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package gce
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"strings"
 	"sync"
 	"time"
@@ -50,6 +52,14 @@ const (
 	operationWaitTimeout       = 5 * time.Second
 	operationPollInterval      = 100 * time.Millisecond
 	nodeAutoprovisioningPrefix = "nodeautoprovisioning"
+
+	gkeOperationWaitTimeout  = 5 * time.Minute
+	gkeOperationPollInterval = 5 * time.Second
+
+	// unknownMigCacheTTL bounds how long an instance that doesn't belong to
+	// any configured MIG is remembered, so that GetMigForInstance doesn't
+	// trigger a full regenerateCache on every subsequent lookup for it.
+	unknownMigCacheTTL = 5 * time.Minute
 )
 
 type migInformation struct {
@@ -57,16 +67,100 @@ type migInformation struct {
 	basename string
 }
 
+// cloudConfig is the single gcfg target used to parse the GCE cloud config
+// file. It declares every section this package understands — [Global],
+// [RateLimit "..."] and [Project "..."] — in one struct, because
+// gcfg.ReadInto/ReadStringInto treats a section absent from the target
+// struct as a fatal parse error: parsing each section into its own strict
+// struct meant that enabling any one of these features broke parsing of
+// the others.
+type cloudConfig struct {
+	Global struct {
+		TokenURL  string `gcfg:"token-url"`
+		TokenBody string `gcfg:"token-body"`
+	}
+
+	// RateLimit is documented on RateLimiterConfig, e.g.:
+	//
+	//	[RateLimit "read"]
+	//	qps = 20
+	//	burst = 40
+	//	[RateLimit "mutate"]
+	//	qps = 2
+	//	burst = 4
+	RateLimit map[string]*struct {
+		QPS   float64
+		Burst int
+	}
+
+	// Project configures the credentials defaultServiceFactory uses for a
+	// project other than the manager's own, e.g.:
+	//
+	//	[Project "other-project"]
+	//	service-account = autoscaler@other-project.iam.gserviceaccount.com
+	//
+	//	[Project "shared-vpc-project"]
+	//	token-url = https://...
+	//	token-body = ...
+	//
+	// A project with no [Project] section uses the manager's default credentials.
+	Project map[string]*struct {
+		// ServiceAccount, if set, is impersonated via the IAM Credentials API
+		// to obtain access tokens scoped to that project.
+		ServiceAccount string `gcfg:"service-account"`
+		// TokenURL and TokenBody, if set, build an alternate token source the
+		// same way the top-level [Global] section does.
+		TokenURL  string `gcfg:"token-url"`
+		TokenBody string `gcfg:"token-body"`
+	}
+}
+
+// parseCloudConfig parses configBytes, which may be empty, into a cloudConfig.
+func parseCloudConfig(configBytes []byte) (*cloudConfig, error) {
+	cfg := &cloudConfig{}
+	if len(configBytes) == 0 {
+		return cfg, nil
+	}
+	if err := gcfg.ReadStringInto(cfg, string(configBytes)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func createRateLimiter(cfg *cloudConfig) RateLimiter {
+	configs := make(map[string]RateLimiterConfig, len(cfg.RateLimit))
+	for op, section := range cfg.RateLimit {
+		configs[op] = RateLimiterConfig{QPS: section.QPS, Burst: section.Burst}
+	}
+	return NewGceRateLimiter(configs)
+}
+
 // GceManager is handles gce communication and data caching.
 type GceManager struct {
 	migs     []*migInformation
 	migCache map[GceRef]*Mig
 
-	gceService *gce.Service
-	gkeService *gke.Service
+	// instancesFromUnknownMig caches, with an expiry time, instances that were
+	// looked up and found not to belong to any configured MIG. It lets
+	// GetMigForInstance recognize unmanaged instances (system pods' nodes,
+	// manually created VMs) without regenerating migCache on every lookup.
+	// At any given time an instance is in at most one of migCache and
+	// instancesFromUnknownMig; if it's in neither, it's simply unresolved.
+	instancesFromUnknownMig map[GceRef]time.Time
+
+	// services holds one *gce.Service per project the manager talks to,
+	// built lazily through serviceFactory so that migs living in sibling
+	// projects (shared VPC, hub-and-spoke) can use their own credentials.
+	services       map[string]*gce.Service
+	serviceFactory ServiceFactory
+	gkeService     *gke.Service
+
+	rateLimiter     RateLimiter
+	metricsExporter MetricsExporter
 
-	cacheMutex sync.Mutex
-	migsMutex  sync.Mutex
+	cacheMutex    sync.Mutex
+	migsMutex     sync.Mutex
+	servicesMutex sync.Mutex
 
 	zone        string
 	projectId   string
@@ -75,16 +169,33 @@ type GceManager struct {
 	templates   *templateBuilder
 }
 
-// CreateGceManager constructs gceManager object.
-func CreateGceManager(configReader io.Reader, mode GcpCloudProviderMode, clusterName string) (*GceManager, error) {
-	// Create Google Compute Engine token.
-	tokenSource := google.ComputeTokenSource("")
+// CreateGceManager constructs gceManager object. metricsExporter may be nil, in
+// which case MIG state is not published to any monitoring backend; pass a
+// MetricsExporter built by NewStackdriverMetricsExporter, or one backed by
+// another system such as Prometheus, to opt in.
+func CreateGceManager(configReader io.Reader, mode GcpCloudProviderMode, clusterName string, metricsExporter MetricsExporter) (*GceManager, error) {
+	if metricsExporter == nil {
+		metricsExporter = noopMetricsExporter{}
+	}
+	var configBytes []byte
 	if configReader != nil {
-		var cfg provider_gce.ConfigFile
-		if err := gcfg.ReadInto(&cfg, configReader); err != nil {
+		var err error
+		configBytes, err = ioutil.ReadAll(configReader)
+		if err != nil {
 			glog.Errorf("Couldn't read config: %v", err)
 			return nil, err
 		}
+	}
+
+	cfg, err := parseCloudConfig(configBytes)
+	if err != nil {
+		glog.Errorf("Couldn't read config: %v", err)
+		return nil, err
+	}
+
+	// Create Google Compute Engine token.
+	tokenSource := google.ComputeTokenSource("")
+	if configBytes != nil {
 		if cfg.Global.TokenURL == "" {
 			glog.Warning("Empty tokenUrl in cloud config")
 		} else {
@@ -94,34 +205,38 @@ func CreateGceManager(configReader io.Reader, mode GcpCloudProviderMode, cluster
 	} else {
 		glog.Infof("Using default TokenSource %#v", tokenSource)
 	}
+
+	rateLimiter := createRateLimiter(cfg)
+	serviceFactory := newServiceFactory(tokenSource, cfg)
+
 	projectId, zone, err := getProjectAndZone()
 	if err != nil {
 		return nil, err
 	}
 	glog.V(1).Infof("GCE projectId=%s zone=%s", projectId, zone)
 
-	// Create Google Compute Engine service.
-	client := oauth2.NewClient(oauth2.NoContext, tokenSource)
-	gceService, err := gce.New(client)
-	if err != nil {
-		return nil, err
-	}
 	manager := &GceManager{
-		migs:        make([]*migInformation, 0),
-		gceService:  gceService,
-		migCache:    make(map[GceRef]*Mig),
-		zone:        zone,
-		projectId:   projectId,
-		clusterName: clusterName,
-		mode:        mode,
-		templates: &templateBuilder{
-			projectId: projectId,
-			zone:      zone,
-			service:   gceService,
-		},
+		migs:                    make([]*migInformation, 0),
+		services:                make(map[string]*gce.Service),
+		serviceFactory:          serviceFactory,
+		migCache:                make(map[GceRef]*Mig),
+		instancesFromUnknownMig: make(map[GceRef]time.Time),
+		rateLimiter:             rateLimiter,
+		metricsExporter:         metricsExporter,
+		zone:                    zone,
+		projectId:               projectId,
+		clusterName:             clusterName,
+		mode:                    mode,
+	}
+	manager.templates = &templateBuilder{gceManager: manager}
+	if _, err := manager.serviceFor(projectId); err != nil {
+		return nil, err
 	}
 
 	if mode == ModeGKE {
+		// GKE node pool management always acts on the cluster's own project,
+		// so it keeps using the manager's default credentials directly.
+		client := oauth2.NewClient(oauth2.NoContext, tokenSource)
 		gkeService, err := gke.New(client)
 		if err != nil {
 			return nil, err
@@ -144,6 +259,33 @@ func CreateGceManager(configReader io.Reader, mode GcpCloudProviderMode, cluster
 	return manager, nil
 }
 
+// acceptRead blocks until a read-class token is available for project, or ctx is done.
+func (m *GceManager) acceptRead(ctx context.Context, project string) error {
+	return m.rateLimiter.Wait(ctx, m.rateLimiter.Accept(project, readOps))
+}
+
+// acceptMutate blocks until a mutate-class token is available for project, or ctx is done.
+func (m *GceManager) acceptMutate(ctx context.Context, project string) error {
+	return m.rateLimiter.Wait(ctx, m.rateLimiter.Accept(project, mutateOps))
+}
+
+// serviceFor returns the *gce.Service to use for project, creating and
+// caching one via serviceFactory on first use.
+func (m *GceManager) serviceFor(project string) (*gce.Service, error) {
+	m.servicesMutex.Lock()
+	defer m.servicesMutex.Unlock()
+
+	if service, found := m.services[project]; found {
+		return service, nil
+	}
+	service, err := m.serviceFactory.NewService(project)
+	if err != nil {
+		return nil, err
+	}
+	m.services[project] = service
+	return service, nil
+}
+
 func (m *GceManager) assertGKE() {
 	if m.mode != ModeGKE {
 		panic(fmt.Errorf("This should run only in GKE mode"))
@@ -158,28 +300,40 @@ func (m *GceManager) fetchAllNodePools() error {
 	if err != nil {
 		return err
 	}
+	registered := make(map[GceRef]bool)
 	for _, nodePool := range nodePoolsResponse.NodePools {
-		autoprovisioned := strings.Contains("name", nodeAutoprovisioningPrefix)
+		autoprovisioned := strings.Contains(nodePool.Name, nodeAutoprovisioningPrefix)
 		autoscaled := nodePool.Autoscaling != nil && nodePool.Autoscaling.Enabled
 		if !autoprovisioned && !autoscaled {
 			continue
 		}
-		// format is
+		// format is either
 		// "https://www.googleapis.com/compute/v1/projects/mwielgus-proj/zones/europe-west1-b/instanceGroupManagers/gke-cluster-1-default-pool-ba78a787-grp"
+		// or, for regional MIGs,
+		// "https://www.googleapis.com/compute/v1/projects/mwielgus-proj/regions/europe-west1/instanceGroupManagers/gke-cluster-1-default-pool-ba78a787-grp"
 		for _, igurl := range nodePool.InstanceGroupUrls {
-			project, zone, name, err := parseGceUrl(igurl, "instanceGroupManagers")
+			project, location, regional, name, err := parseGceUrl(igurl, "instanceGroupManagers")
 			if err != nil {
 				return err
 			}
+			ref := GceRef{Name: name, Project: project}
+			if regional {
+				// A regional node pool is backed by a single regional MIG, even
+				// though GKE may report one InstanceGroupUrl per zone in the region.
+				ref.Region = location
+				if registered[ref] {
+					continue
+				}
+			} else {
+				ref.Zone = location
+			}
+
 			mig := &Mig{
-				GceRef: GceRef{
-					Name:    name,
-					Zone:    zone,
-					Project: project,
-				},
+				GceRef:          ref,
 				gceManager:      m,
 				exist:           true,
 				autoprovisioned: autoprovisioned,
+				nodePoolName:    nodePool.Name,
 			}
 			if autoscaled {
 				mig.minSize = int(nodePool.Autoscaling.MinNodeCount)
@@ -189,12 +343,45 @@ func (m *GceManager) fetchAllNodePools() error {
 				mig.maxSize = maxAutoprovisionedSize
 			}
 			m.RegisterMig(mig)
+			registered[ref] = true
 		}
-		// TODO - unregister migs
 	}
+	m.unregisterMigsNotIn(registered)
 	return nil
 }
 
+// unregisterMigsNotIn removes every registered mig whose GceRef is not in
+// present, whatever registered it, along with their stale entries in
+// migCache. fetchAllNodePools and DeleteNodePool use it to drop migs whose
+// node pool no longer exists.
+func (m *GceManager) unregisterMigsNotIn(present map[GceRef]bool) {
+	m.migsMutex.Lock()
+	updated := make([]*migInformation, 0, len(m.migs))
+	removed := make(map[GceRef]bool)
+	for _, migInfo := range m.migs {
+		if present[migInfo.config.GceRef] {
+			updated = append(updated, migInfo)
+			continue
+		}
+		glog.V(1).Infof("Unregistering %s/%s/%s, node pool no longer exists",
+			migInfo.config.Project, migInfo.config.Zone, migInfo.config.Name)
+		removed[migInfo.config.GceRef] = true
+	}
+	m.migs = updated
+	m.migsMutex.Unlock()
+
+	if len(removed) == 0 {
+		return
+	}
+	m.cacheMutex.Lock()
+	defer m.cacheMutex.Unlock()
+	for instance, mig := range m.migCache {
+		if removed[mig.GceRef] {
+			delete(m.migCache, instance)
+		}
+	}
+}
+
 // RegisterMig registers mig in Gce Manager. Returns true if the node group didn't exist before.
 func (m *GceManager) RegisterMig(mig *Mig) bool {
 	m.migsMutex.Lock()
@@ -216,6 +403,11 @@ func (m *GceManager) RegisterMig(mig *Mig) bool {
 		})
 	}
 
+	ctx := context.Background()
+	if err := m.acceptRead(ctx, mig.Project); err != nil {
+		glog.Errorf("Failed to build template for %s: %v", mig.Name, err)
+		return !updated
+	}
 	template, err := m.templates.getMigTemplate(mig)
 	if err != nil {
 		glog.Errorf("Failed to build template for %s", mig.Name)
@@ -230,7 +422,22 @@ func (m *GceManager) RegisterMig(mig *Mig) bool {
 
 // GetMigSize gets MIG size.
 func (m *GceManager) GetMigSize(mig *Mig) (int64, error) {
-	igm, err := m.gceService.InstanceGroupManagers.Get(mig.Project, mig.Zone, mig.Name).Do()
+	ctx := context.Background()
+	if err := m.acceptRead(ctx, mig.Project); err != nil {
+		return -1, err
+	}
+	service, err := m.serviceFor(mig.Project)
+	if err != nil {
+		return -1, err
+	}
+	if mig.IsRegional() {
+		igm, err := service.RegionInstanceGroupManagers.Get(mig.Project, mig.Region, mig.Name).Do()
+		if err != nil {
+			return -1, err
+		}
+		return igm.TargetSize, nil
+	}
+	igm, err := service.InstanceGroupManagers.Get(mig.Project, mig.Zone, mig.Name).Do()
 	if err != nil {
 		return -1, err
 	}
@@ -240,29 +447,129 @@ func (m *GceManager) GetMigSize(mig *Mig) (int64, error) {
 // SetMigSize sets MIG size.
 func (m *GceManager) SetMigSize(mig *Mig, size int64) error {
 	glog.V(0).Infof("Setting mig size %s to %d", mig.Id(), size)
-	op, err := m.gceService.InstanceGroupManagers.Resize(mig.Project, mig.Zone, mig.Name, size).Do()
+	previousSize, err := m.GetMigSize(mig)
 	if err != nil {
 		return err
 	}
-	if err := m.waitForOp(op, mig.Project, mig.Zone); err != nil {
+
+	ctx := context.Background()
+	if err := m.acceptMutate(ctx, mig.Project); err != nil {
 		return err
 	}
+	service, err := m.serviceFor(mig.Project)
+	if err != nil {
+		return err
+	}
+	if mig.IsRegional() {
+		op, err := service.RegionInstanceGroupManagers.Resize(mig.Project, mig.Region, mig.Name, size).Do()
+		if err != nil {
+			return err
+		}
+		if err := m.waitForRegionOp(ctx, "resize", op, mig.Project, mig.Region); err != nil {
+			return err
+		}
+	} else {
+		op, err := service.InstanceGroupManagers.Resize(mig.Project, mig.Zone, mig.Name, size).Do()
+		if err != nil {
+			return err
+		}
+		if err := m.waitForOp(ctx, "resize", op, mig.Project, mig.Zone); err != nil {
+			return err
+		}
+	}
+	m.metricsExporter.RecordMigTargetSize(mig, size)
+	m.metricsExporter.RecordScaleTime(mig, size > previousSize, time.Now())
 	return nil
 }
 
-func (m *GceManager) waitForOp(operation *gce.Operation, project string, zone string) error {
-	for start := time.Now(); time.Since(start) < operationWaitTimeout; time.Sleep(operationPollInterval) {
+// waitForOp polls until operation completes or operationWaitTimeout expires,
+// recording the operation's latency under kind (e.g. "resize", "delete-instances").
+func (m *GceManager) waitForOp(ctx context.Context, kind string, operation *gce.Operation, project string, zone string) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, operationWaitTimeout)
+	defer cancel()
+	service, err := m.serviceFor(project)
+	if err != nil {
+		return err
+	}
+	for {
 		glog.V(4).Infof("Waiting for operation %s %s %s", project, zone, operation.Name)
-		if op, err := m.gceService.ZoneOperations.Get(project, zone, operation.Name).Do(); err == nil {
+		if err := m.acceptRead(ctx, project); err != nil {
+			return err
+		}
+		if op, err := service.ZoneOperations.Get(project, zone, operation.Name).Do(); err == nil {
 			glog.V(4).Infof("Operation %s %s %s status: %s", project, zone, operation.Name, op.Status)
 			if op.Status == "DONE" {
+				m.metricsExporter.RecordOperationLatency(kind, time.Since(start))
 				return nil
 			}
 		} else {
 			glog.Warningf("Error while getting operation %s on %s: %v", operation.Name, operation.TargetLink, err)
 		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Timeout while waiting for operation %s on %s to complete.", operation.Name, operation.TargetLink)
+		case <-time.After(operationPollInterval):
+		}
+	}
+}
+
+// waitForRegionOp is the regional counterpart of waitForOp, for operations
+// returned by RegionInstanceGroupManagers calls.
+func (m *GceManager) waitForRegionOp(ctx context.Context, kind string, operation *gce.Operation, project string, region string) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, operationWaitTimeout)
+	defer cancel()
+	service, err := m.serviceFor(project)
+	if err != nil {
+		return err
+	}
+	for {
+		glog.V(4).Infof("Waiting for operation %s %s %s", project, region, operation.Name)
+		if err := m.acceptRead(ctx, project); err != nil {
+			return err
+		}
+		if op, err := service.RegionOperations.Get(project, region, operation.Name).Do(); err == nil {
+			glog.V(4).Infof("Operation %s %s %s status: %s", project, region, operation.Name, op.Status)
+			if op.Status == "DONE" {
+				m.metricsExporter.RecordOperationLatency(kind, time.Since(start))
+				return nil
+			}
+		} else {
+			glog.Warningf("Error while getting operation %s on %s: %v", operation.Name, operation.TargetLink, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Timeout while waiting for operation %s on %s to complete.", operation.Name, operation.TargetLink)
+		case <-time.After(operationPollInterval):
+		}
+	}
+}
+
+// waitForGkeOp polls a GKE (node pool) operation until it completes or
+// gkeOperationWaitTimeout expires.
+func (m *GceManager) waitForGkeOp(ctx context.Context, operation *gke.Operation) error {
+	ctx, cancel := context.WithTimeout(ctx, gkeOperationWaitTimeout)
+	defer cancel()
+	for {
+		glog.V(4).Infof("Waiting for GKE operation %s %s", m.zone, operation.Name)
+		if err := m.acceptRead(ctx, m.projectId); err != nil {
+			return err
+		}
+		if op, err := m.gkeService.Projects.Zones.Operations.Get(m.projectId, m.zone, operation.Name).Do(); err == nil {
+			glog.V(4).Infof("GKE operation %s status: %s", operation.Name, op.Status)
+			if op.Status == "DONE" {
+				return nil
+			}
+		} else {
+			glog.Warningf("Error while getting GKE operation %s: %v", operation.Name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Timeout while waiting for GKE operation %s to complete.", operation.Name)
+		case <-time.After(gkeOperationPollInterval):
+		}
 	}
-	return fmt.Errorf("Timeout while waiting for operation %s on %s to complete.", operation.Name, operation.TargetLink)
 }
 
 // DeleteInstances deletes the given instances. All instances must be controlled by the same MIG.
@@ -291,14 +598,28 @@ func (m *GceManager) DeleteInstances(instances []*GceRef) error {
 		req.Instances = append(req.Instances, GenerateInstanceUrl(instance.Project, instance.Zone, instance.Name))
 	}
 
-	op, err := m.gceService.InstanceGroupManagers.DeleteInstances(commonMig.Project, commonMig.Zone, commonMig.Name, &req).Do()
+	ctx := context.Background()
+	if err := m.acceptMutate(ctx, commonMig.Project); err != nil {
+		return err
+	}
+	service, err := m.serviceFor(commonMig.Project)
 	if err != nil {
 		return err
 	}
-	if err := m.waitForOp(op, commonMig.Project, commonMig.Zone); err != nil {
+
+	if commonMig.IsRegional() {
+		op, err := service.RegionInstanceGroupManagers.DeleteInstances(commonMig.Project, commonMig.Region, commonMig.Name, &req).Do()
+		if err != nil {
+			return err
+		}
+		return m.waitForRegionOp(ctx, "delete-instances", op, commonMig.Project, commonMig.Region)
+	}
+
+	op, err := service.InstanceGroupManagers.DeleteInstances(commonMig.Project, commonMig.Zone, commonMig.Name, &req).Do()
+	if err != nil {
 		return err
 	}
-	return nil
+	return m.waitForOp(ctx, "delete-instances", op, commonMig.Project, commonMig.Zone)
 }
 
 func (m *GceManager) getMigs() []*migInformation {
@@ -321,10 +642,22 @@ func (m *GceManager) GetMigForInstance(instance *GceRef) (*Mig, error) {
 	if mig, found := m.migCache[*instance]; found {
 		return mig, nil
 	}
+	if expiry, found := m.instancesFromUnknownMig[*instance]; found {
+		if time.Now().Before(expiry) {
+			return nil, nil
+		}
+		delete(m.instancesFromUnknownMig, *instance)
+	}
 
 	for _, mig := range m.getMigs() {
+		sameLocation := mig.config.Zone == instance.Zone
+		if mig.config.IsRegional() {
+			// A regional mig's instances are zonal; match by the instance's
+			// zone falling within the mig's region rather than an exact zone.
+			sameLocation = zoneToRegion(instance.Zone) == mig.config.Region
+		}
 		if mig.config.Project == instance.Project &&
-			mig.config.Zone == instance.Zone &&
+			sameLocation &&
 			strings.HasPrefix(instance.Name, mig.basename) {
 			if err := m.regenerateCache(); err != nil {
 				return nil, fmt.Errorf("Error while looking for MIG for instance %+v, error: %v", *instance, err)
@@ -335,44 +668,154 @@ func (m *GceManager) GetMigForInstance(instance *GceRef) (*Mig, error) {
 			return nil, fmt.Errorf("Instance %+v does not belong to any configured MIG", *instance)
 		}
 	}
-	// Instance doesn't belong to any configured mig.
+	// Instance doesn't belong to any configured mig. Remember that for
+	// unknownMigCacheTTL so repeated lookups don't keep regenerating the cache.
+	m.instancesFromUnknownMig[*instance] = time.Now().Add(unknownMigCacheTTL)
 	return nil, nil
 }
 
+// regenerateCache rebuilds migCache from scratch. Migs are grouped by project
+// and each project's migs are refreshed concurrently, so that one slow or
+// heavily rate-limited project can't hold up the others.
 func (m *GceManager) regenerateCache() error {
-	newMigCache := make(map[GceRef]*Mig)
+	start := time.Now()
+	defer func() {
+		m.metricsExporter.RecordCacheRegenerationDuration(time.Since(start))
+	}()
 
+	byProject := make(map[string][]*migInformation)
 	for _, migInfo := range m.getMigs() {
+		byProject[migInfo.config.Project] = append(byProject[migInfo.config.Project], migInfo)
+	}
+
+	var wg sync.WaitGroup
+	var resultMutex sync.Mutex
+	newMigCache := make(map[GceRef]*Mig)
+	newBasenames := make(map[GceRef]string)
+	var firstErr error
+
+	for project, migInfos := range byProject {
+		wg.Add(1)
+		go func(project string, migInfos []*migInformation) {
+			defer wg.Done()
+			entries, basenames, err := m.regenerateCacheForProject(project, migInfos)
+
+			resultMutex.Lock()
+			defer resultMutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for ref, mig := range entries {
+				newMigCache[ref] = mig
+			}
+			for ref, basename := range basenames {
+				newBasenames[ref] = basename
+			}
+		}(project, migInfos)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	m.migCache = newMigCache
+	// Instances that were previously unresolved may now belong to a mig that
+	// was just discovered (or may genuinely still belong to none); either way
+	// the old negative results are stale, so drop them and let the next
+	// GetMigForInstance call re-derive them.
+	m.instancesFromUnknownMig = make(map[GceRef]time.Time)
+
+	// getMigs() hands out copies, so regenerateCacheForProject's basename
+	// writes land on those copies; persist them onto the real m.migs here.
+	m.migsMutex.Lock()
+	for _, migInfo := range m.migs {
+		if basename, found := newBasenames[migInfo.config.GceRef]; found {
+			migInfo.basename = basename
+		}
+	}
+	m.migsMutex.Unlock()
+	return nil
+}
+
+// regenerateCacheForProject fetches fresh MIG info for every mig in migInfos,
+// all of which must belong to project, and returns the resulting
+// instance-to-mig entries together with each mig's observed base instance name.
+func (m *GceManager) regenerateCacheForProject(project string, migInfos []*migInformation) (map[GceRef]*Mig, map[GceRef]string, error) {
+	ctx := context.Background()
+	if err := m.acceptRead(ctx, project); err != nil {
+		return nil, nil, err
+	}
+	service, err := m.serviceFor(project)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make(map[GceRef]*Mig)
+	basenames := make(map[GceRef]string)
+	for _, migInfo := range migInfos {
 		mig := migInfo.config
 		glog.V(4).Infof("Regenerating MIG information for %s %s %s", mig.Project, mig.Zone, mig.Name)
 
-		instanceGroupManager, err := m.gceService.InstanceGroupManagers.Get(mig.Project, mig.Zone, mig.Name).Do()
-		if err != nil {
-			return err
-		}
-		migInfo.basename = instanceGroupManager.BaseInstanceName
+		var baseInstanceName string
+		var instances *gce.InstanceGroupManagersListManagedInstancesResponse
+		if mig.IsRegional() {
+			instanceGroupManager, err := service.RegionInstanceGroupManagers.Get(mig.Project, mig.Region, mig.Name).Do()
+			if err != nil {
+				return nil, nil, err
+			}
+			baseInstanceName = instanceGroupManager.BaseInstanceName
 
-		instances, err := m.gceService.InstanceGroupManagers.ListManagedInstances(mig.Project, mig.Zone, mig.Name).Do()
-		if err != nil {
-			glog.V(4).Infof("Failed MIG info request for %s %s %s: %v", mig.Project, mig.Zone, mig.Name, err)
-			return err
+			instances, err = service.RegionInstanceGroupManagers.ListManagedInstances(mig.Project, mig.Region, mig.Name).Do()
+			if err != nil {
+				glog.V(4).Infof("Failed MIG info request for %s %s %s: %v", mig.Project, mig.Region, mig.Name, err)
+				return nil, nil, err
+			}
+		} else {
+			instanceGroupManager, err := service.InstanceGroupManagers.Get(mig.Project, mig.Zone, mig.Name).Do()
+			if err != nil {
+				return nil, nil, err
+			}
+			baseInstanceName = instanceGroupManager.BaseInstanceName
+
+			instances, err = service.InstanceGroupManagers.ListManagedInstances(mig.Project, mig.Zone, mig.Name).Do()
+			if err != nil {
+				glog.V(4).Infof("Failed MIG info request for %s %s %s: %v", mig.Project, mig.Zone, mig.Name, err)
+				return nil, nil, err
+			}
 		}
+		migInfo.basename = baseInstanceName
+		basenames[mig.GceRef] = baseInstanceName
+		m.metricsExporter.RecordMigNodeCount(mig, len(instances.ManagedInstances))
+
 		for _, instance := range instances.ManagedInstances {
-			project, zone, name, err := ParseInstanceUrl(instance.Instance)
+			instProject, zone, name, err := ParseInstanceUrl(instance.Instance)
 			if err != nil {
-				return err
+				return nil, nil, err
 			}
-			newMigCache[GceRef{Project: project, Zone: zone, Name: name}] = mig
+			entries[GceRef{Project: instProject, Zone: zone, Name: name}] = mig
 		}
 	}
-
-	m.migCache = newMigCache
-	return nil
+	return entries, basenames, nil
 }
 
 // GetMigNodes returns mig nodes.
 func (m *GceManager) GetMigNodes(mig *Mig) ([]string, error) {
-	instances, err := m.gceService.InstanceGroupManagers.ListManagedInstances(mig.Project, mig.Zone, mig.Name).Do()
+	if err := m.acceptRead(context.Background(), mig.Project); err != nil {
+		return []string{}, err
+	}
+	service, err := m.serviceFor(mig.Project)
+	if err != nil {
+		return []string{}, err
+	}
+	var instances *gce.InstanceGroupManagersListManagedInstancesResponse
+	if mig.IsRegional() {
+		instances, err = service.RegionInstanceGroupManagers.ListManagedInstances(mig.Project, mig.Region, mig.Name).Do()
+	} else {
+		instances, err = service.InstanceGroupManagers.ListManagedInstances(mig.Project, mig.Zone, mig.Name).Do()
+	}
 	if err != nil {
 		return []string{}, err
 	}
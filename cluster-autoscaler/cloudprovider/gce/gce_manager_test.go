@@ -0,0 +1,113 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestManager() *GceManager {
+	return &GceManager{
+		migs:                    make([]*migInformation, 0),
+		migCache:                make(map[GceRef]*Mig),
+		instancesFromUnknownMig: make(map[GceRef]time.Time),
+		metricsExporter:         noopMetricsExporter{},
+	}
+}
+
+func TestGetMigForInstance_CacheHit(t *testing.T) {
+	manager := newTestManager()
+	instance := GceRef{Project: "proj", Zone: "us-central1-b", Name: "inst-1"}
+	mig := &Mig{GceRef: GceRef{Project: "proj", Zone: "us-central1-b", Name: "mig-1"}}
+	manager.migCache[instance] = mig
+
+	got, err := manager.GetMigForInstance(&instance)
+	assert.NoError(t, err)
+	assert.Same(t, mig, got)
+}
+
+func TestGetMigForInstance_UnknownMigStillWithinTTL(t *testing.T) {
+	manager := newTestManager()
+	instance := GceRef{Project: "proj", Zone: "us-central1-b", Name: "inst-1"}
+	manager.instancesFromUnknownMig[instance] = time.Now().Add(unknownMigCacheTTL)
+
+	got, err := manager.GetMigForInstance(&instance)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	_, stillCached := manager.instancesFromUnknownMig[instance]
+	assert.True(t, stillCached, "unexpired negative result should not be evicted")
+}
+
+func TestGetMigForInstance_UnknownMigExpiredIsRederived(t *testing.T) {
+	manager := newTestManager()
+	instance := GceRef{Project: "proj", Zone: "us-central1-b", Name: "inst-1"}
+	manager.instancesFromUnknownMig[instance] = time.Now().Add(-time.Minute)
+
+	got, err := manager.GetMigForInstance(&instance)
+	assert.NoError(t, err)
+	assert.Nil(t, got, "instance still belongs to no configured mig")
+
+	expiry, found := manager.instancesFromUnknownMig[instance]
+	assert.True(t, found, "expired entry should be refreshed, not left expired")
+	assert.True(t, expiry.After(time.Now()), "refreshed expiry should be in the future")
+}
+
+func TestGetMigForInstance_UnmanagedInstanceColocatedWithMig(t *testing.T) {
+	manager := newTestManager()
+	mig := &Mig{GceRef: GceRef{Project: "proj", Zone: "us-central1-b", Name: "mig-1"}}
+	manager.migs = []*migInformation{
+		{config: mig, basename: "gke-mig-1-"},
+	}
+
+	// Same project and zone as the mig, but its name doesn't match the mig's
+	// base instance name, as for a system pod's node or a manually created VM.
+	instance := GceRef{Project: "proj", Zone: "us-central1-b", Name: "manual-vm"}
+
+	got, err := manager.GetMigForInstance(&instance)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	_, cachedAsUnknown := manager.instancesFromUnknownMig[instance]
+	assert.True(t, cachedAsUnknown, "colocated instance with a non-matching basename must be recorded as unknown, not misattributed to the mig")
+}
+
+func TestUnregisterMigsNotIn(t *testing.T) {
+	manager := newTestManager()
+	keep := &Mig{GceRef: GceRef{Project: "proj", Zone: "us-central1-b", Name: "keep"}}
+	remove := &Mig{GceRef: GceRef{Project: "proj", Zone: "us-central1-b", Name: "remove"}}
+	manager.migs = []*migInformation{
+		{config: keep},
+		{config: remove},
+	}
+
+	keepInstance := GceRef{Project: "proj", Zone: "us-central1-b", Name: "keep-0"}
+	removeInstance := GceRef{Project: "proj", Zone: "us-central1-b", Name: "remove-0"}
+	manager.migCache[keepInstance] = keep
+	manager.migCache[removeInstance] = remove
+
+	manager.unregisterMigsNotIn(map[GceRef]bool{keep.GceRef: true})
+
+	assert.Len(t, manager.migs, 1)
+	assert.Equal(t, keep.GceRef, manager.migs[0].config.GceRef)
+
+	_, keepStillCached := manager.migCache[keepInstance]
+	assert.True(t, keepStillCached, "cache entries for migs that remain registered must survive")
+	_, removedStillCached := manager.migCache[removeInstance]
+	assert.False(t, removedStillCached, "cache entries for unregistered migs must be dropped")
+}
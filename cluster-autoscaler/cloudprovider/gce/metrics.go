@@ -0,0 +1,228 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/golang/glog"
+	monitoring "google.golang.org/api/monitoring/v3"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	metricDomain                = "custom.googleapis.com/cluster-autoscaler/"
+	defaultMetricsFlushInterval = 60 * time.Second
+	maxTimeSeriesPerRequest     = 200
+)
+
+// MetricsExporter publishes the MIG state GceManager computes to an external
+// monitoring backend. Implementations must be safe for concurrent use.
+// Passing a nil MetricsExporter into CreateGceManager disables metrics export;
+// the interface is also implementable against other backends (e.g. Prometheus).
+type MetricsExporter interface {
+	// RecordMigTargetSize records the current target size of a mig.
+	RecordMigTargetSize(mig *Mig, targetSize int64)
+	// RecordMigNodeCount records the number of instances currently managed by a mig.
+	RecordMigNodeCount(mig *Mig, nodeCount int)
+	// RecordScaleTime records the time of the last scale-up or scale-down of a mig.
+	RecordScaleTime(mig *Mig, scaleUp bool, when time.Time)
+	// RecordOperationLatency records how long a GCE operation of the given kind
+	// (e.g. "resize", "delete-instances") took to complete.
+	RecordOperationLatency(kind string, latency time.Duration)
+	// RecordCacheRegenerationDuration records how long a regenerateCache pass took.
+	RecordCacheRegenerationDuration(duration time.Duration)
+}
+
+// noopMetricsExporter is used when the caller doesn't supply a MetricsExporter.
+type noopMetricsExporter struct{}
+
+func (noopMetricsExporter) RecordMigTargetSize(mig *Mig, targetSize int64)        {}
+func (noopMetricsExporter) RecordMigNodeCount(mig *Mig, nodeCount int)            {}
+func (noopMetricsExporter) RecordScaleTime(mig *Mig, scaleUp bool, when time.Time) {}
+func (noopMetricsExporter) RecordOperationLatency(kind string, latency time.Duration) {}
+func (noopMetricsExporter) RecordCacheRegenerationDuration(duration time.Duration) {}
+
+type metricPoint struct {
+	metric    string
+	value     float64
+	labels    map[string]string
+	timestamp time.Time
+}
+
+// stackdriverMetricsExporter is the default MetricsExporter. It batches points
+// and flushes them to Cloud Monitoring on a fixed interval, lazily creating a
+// metricDescriptor the first time each metric name is written.
+type stackdriverMetricsExporter struct {
+	projectId string
+	service   *monitoring.Service
+
+	mutex       sync.Mutex
+	pending     []*metricPoint
+	descriptors map[string]bool
+}
+
+// NewStackdriverMetricsExporter builds a MetricsExporter that publishes to
+// Cloud Monitoring under custom.googleapis.com/cluster-autoscaler/. client
+// should be authenticated the same way as the GceManager's own per-project
+// services (see CreateGceManager). flushInterval <= 0 defaults to 60s.
+func NewStackdriverMetricsExporter(client *http.Client, flushInterval time.Duration) (MetricsExporter, error) {
+	projectId, err := metadata.ProjectID()
+	if err != nil {
+		return nil, err
+	}
+	service, err := monitoring.New(client)
+	if err != nil {
+		return nil, err
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultMetricsFlushInterval
+	}
+
+	exporter := &stackdriverMetricsExporter{
+		projectId:   projectId,
+		service:     service,
+		descriptors: make(map[string]bool),
+	}
+	go wait.Forever(exporter.flush, flushInterval)
+	return exporter, nil
+}
+
+func (e *stackdriverMetricsExporter) record(metric string, value float64, labels map[string]string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.pending = append(e.pending, &metricPoint{
+		metric:    metric,
+		value:     value,
+		labels:    labels,
+		timestamp: time.Now(),
+	})
+}
+
+func (e *stackdriverMetricsExporter) RecordMigTargetSize(mig *Mig, targetSize int64) {
+	e.record("mig_target_size", float64(targetSize), migLabels(mig))
+}
+
+func (e *stackdriverMetricsExporter) RecordMigNodeCount(mig *Mig, nodeCount int) {
+	e.record("mig_node_count", float64(nodeCount), migLabels(mig))
+}
+
+func (e *stackdriverMetricsExporter) RecordScaleTime(mig *Mig, scaleUp bool, when time.Time) {
+	metric := "last_scale_down_time"
+	if scaleUp {
+		metric = "last_scale_up_time"
+	}
+	e.record(metric, float64(when.Unix()), migLabels(mig))
+}
+
+func (e *stackdriverMetricsExporter) RecordOperationLatency(kind string, latency time.Duration) {
+	e.record("operation_latency_seconds", latency.Seconds(), map[string]string{"operation": kind})
+}
+
+func (e *stackdriverMetricsExporter) RecordCacheRegenerationDuration(duration time.Duration) {
+	e.record("cache_regeneration_duration_seconds", duration.Seconds(), nil)
+}
+
+func migLabels(mig *Mig) map[string]string {
+	return map[string]string{
+		"project": mig.Project,
+		"mig":     mig.Name,
+	}
+}
+
+// flush drains the pending points and writes them to Cloud Monitoring,
+// creating any missing metricDescriptors first and batching TimeSeries.Create
+// calls to at most maxTimeSeriesPerRequest points.
+func (e *stackdriverMetricsExporter) flush() {
+	e.mutex.Lock()
+	points := e.pending
+	e.pending = nil
+	e.mutex.Unlock()
+
+	if len(points) == 0 {
+		return
+	}
+
+	series := make([]*monitoring.TimeSeries, 0, len(points))
+	for _, point := range points {
+		if err := e.ensureMetricDescriptor(point.metric); err != nil {
+			glog.Errorf("Failed to create metric descriptor for %s: %v", point.metric, err)
+			continue
+		}
+		series = append(series, &monitoring.TimeSeries{
+			Metric: &monitoring.Metric{
+				Type:   metricDomain + point.metric,
+				Labels: point.labels,
+			},
+			Resource: &monitoring.MonitoredResource{
+				Type:   "global",
+				Labels: map[string]string{"project_id": e.projectId},
+			},
+			Points: []*monitoring.Point{
+				{
+					Interval: &monitoring.TimeInterval{
+						EndTime: point.timestamp.Format(time.RFC3339),
+					},
+					Value: &monitoring.TypedValue{
+						DoubleValue: &point.value,
+					},
+				},
+			},
+		})
+	}
+
+	for start := 0; start < len(series); start += maxTimeSeriesPerRequest {
+		end := start + maxTimeSeriesPerRequest
+		if end > len(series) {
+			end = len(series)
+		}
+		req := &monitoring.CreateTimeSeriesRequest{TimeSeries: series[start:end]}
+		name := fmt.Sprintf("projects/%s", e.projectId)
+		if _, err := e.service.Projects.TimeSeries.Create(name, req).Do(); err != nil {
+			glog.Errorf("Failed to write %d time series to Cloud Monitoring: %v", end-start, err)
+		}
+	}
+}
+
+func (e *stackdriverMetricsExporter) ensureMetricDescriptor(metric string) error {
+	e.mutex.Lock()
+	if e.descriptors[metric] {
+		e.mutex.Unlock()
+		return nil
+	}
+	e.mutex.Unlock()
+
+	descriptor := &monitoring.MetricDescriptor{
+		Type:       metricDomain + metric,
+		MetricKind: "GAUGE",
+		ValueType:  "DOUBLE",
+		Name:       metric,
+	}
+	name := fmt.Sprintf("projects/%s", e.projectId)
+	if _, err := e.service.Projects.MetricDescriptors.Create(name, descriptor).Do(); err != nil {
+		return err
+	}
+
+	e.mutex.Lock()
+	e.descriptors[metric] = true
+	e.mutex.Unlock()
+	return nil
+}
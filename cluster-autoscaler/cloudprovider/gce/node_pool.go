@@ -0,0 +1,150 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"fmt"
+
+	gke "google.golang.org/api/container/v1"
+)
+
+// NodePoolSpec describes a GKE node pool to be created by the node
+// auto-provisioning subsystem.
+type NodePoolSpec struct {
+	// Name is the name of the node pool to create.
+	Name string
+	// MachineType is the GCE machine type backing the pool's instances.
+	MachineType string
+	// DiskSizeGb is the boot disk size, in GB, of the pool's instances.
+	DiskSizeGb int64
+	// Accelerators lists any accelerators (e.g. GPUs) attached to each instance.
+	Accelerators []*gke.AcceleratorConfig
+	// Taints are applied to every node in the pool.
+	Taints []*gke.NodeTaint
+	// Labels are applied to every node in the pool.
+	Labels map[string]string
+	// MinNodeCount and MaxNodeCount bound the pool's autoscaling range.
+	MinNodeCount int64
+	MaxNodeCount int64
+}
+
+// CreateNodePool creates a new, autoscaled GKE node pool matching spec and
+// registers the resulting Mig so it can be managed like any other node group.
+func (m *GceManager) CreateNodePool(spec NodePoolSpec) (*Mig, error) {
+	m.assertGKE()
+
+	request := &gke.CreateNodePoolRequest{
+		NodePool: &gke.NodePool{
+			Name: spec.Name,
+			Config: &gke.NodeConfig{
+				MachineType:  spec.MachineType,
+				DiskSizeGb:   spec.DiskSizeGb,
+				Accelerators: spec.Accelerators,
+				Taints:       spec.Taints,
+				Labels:       spec.Labels,
+			},
+			InitialNodeCount: spec.MinNodeCount,
+			Autoscaling: &gke.NodePoolAutoscaling{
+				Enabled:      true,
+				MinNodeCount: spec.MinNodeCount,
+				MaxNodeCount: spec.MaxNodeCount,
+			},
+		},
+	}
+
+	ctx := context.Background()
+	if err := m.acceptMutate(ctx, m.projectId); err != nil {
+		return nil, err
+	}
+	op, err := m.gkeService.Projects.Zones.Clusters.NodePools.Create(m.projectId, m.zone, m.clusterName, request).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.waitForGkeOp(ctx, op); err != nil {
+		return nil, err
+	}
+
+	nodePool, err := m.gkeService.Projects.Zones.Clusters.NodePools.Get(m.projectId, m.zone, m.clusterName, spec.Name).Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(nodePool.InstanceGroupUrls) == 0 {
+		return nil, fmt.Errorf("node pool %s has no instance groups", spec.Name)
+	}
+
+	project, location, regional, name, err := parseGceUrl(nodePool.InstanceGroupUrls[0], "instanceGroupManagers")
+	if err != nil {
+		return nil, err
+	}
+	ref := GceRef{Name: name, Project: project}
+	if regional {
+		ref.Region = location
+	} else {
+		ref.Zone = location
+	}
+
+	mig := &Mig{
+		GceRef:          ref,
+		gceManager:      m,
+		exist:           true,
+		autoprovisioned: true,
+		nodePoolName:    spec.Name,
+		minSize:         int(spec.MinNodeCount),
+		maxSize:         int(spec.MaxNodeCount),
+	}
+	m.RegisterMig(mig)
+	return mig, nil
+}
+
+// DeleteNodePool deletes the GKE node pool backing mig and unregisters it.
+func (m *GceManager) DeleteNodePool(mig *Mig) error {
+	m.assertGKE()
+	if !mig.Autoprovisioned() {
+		return fmt.Errorf("cannot delete node pool for a non-autoprovisioned mig %s", mig.Id())
+	}
+
+	ctx := context.Background()
+	if err := m.acceptMutate(ctx, mig.Project); err != nil {
+		return err
+	}
+	op, err := m.gkeService.Projects.Zones.Clusters.NodePools.Delete(m.projectId, m.zone, m.clusterName, mig.nodePoolName).Do()
+	if err != nil {
+		return err
+	}
+	if err := m.waitForGkeOp(ctx, op); err != nil {
+		return err
+	}
+
+	m.unregisterMigsNotIn(m.migRefsExcept(mig.GceRef))
+	return nil
+}
+
+// migRefsExcept returns a set of every currently registered GceRef other than excluded,
+// for use with unregisterMigsNotIn.
+func (m *GceManager) migRefsExcept(excluded GceRef) map[GceRef]bool {
+	m.migsMutex.Lock()
+	defer m.migsMutex.Unlock()
+
+	refs := make(map[GceRef]bool, len(m.migs))
+	for _, migInfo := range m.migs {
+		if migInfo.config.GceRef != excluded {
+			refs[migInfo.config.GceRef] = true
+		}
+	}
+	return refs
+}
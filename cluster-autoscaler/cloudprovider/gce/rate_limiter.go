@@ -0,0 +1,122 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	readOps   = "read"
+	mutateOps = "mutate"
+)
+
+// RateLimiterConfig holds the token-bucket parameters for a single operation class.
+type RateLimiterConfig struct {
+	// QPS is the steady-state number of calls per second allowed.
+	QPS float64
+	// Burst is the maximum number of calls that can be made in a single burst.
+	Burst int
+}
+
+var defaultRateLimiterConfigs = map[string]RateLimiterConfig{
+	readOps:   {QPS: 10, Burst: 20},
+	mutateOps: {QPS: 2, Burst: 4},
+}
+
+// RateLimiter throttles outbound GCE/GKE API calls so that a busy autoscaler
+// does not exceed its project's API quota. Buckets are kept per
+// {project, operation class} pair.
+type RateLimiter interface {
+	// Accept reserves the next available slot for an operation of the given
+	// class (e.g. "read", "mutate") against the given project. It does not block.
+	Accept(project string, op string) *rate.Reservation
+	// Wait blocks until res may proceed, or ctx is done.
+	Wait(ctx context.Context, res *rate.Reservation) error
+}
+
+type rateLimiterKey struct {
+	project string
+	op      string
+}
+
+// gceRateLimiter is the default, token-bucket backed RateLimiter.
+type gceRateLimiter struct {
+	mutex    sync.Mutex
+	configs  map[string]RateLimiterConfig
+	limiters map[rateLimiterKey]*rate.Limiter
+}
+
+// NewGceRateLimiter builds a token-bucket RateLimiter. configs overrides the
+// default per-operation-class QPS/burst; operation classes missing from configs
+// fall back to the built-in defaults.
+func NewGceRateLimiter(configs map[string]RateLimiterConfig) RateLimiter {
+	merged := make(map[string]RateLimiterConfig, len(defaultRateLimiterConfigs))
+	for op, cfg := range defaultRateLimiterConfigs {
+		merged[op] = cfg
+	}
+	for op, cfg := range configs {
+		merged[op] = cfg
+	}
+	return &gceRateLimiter{
+		configs:  merged,
+		limiters: make(map[rateLimiterKey]*rate.Limiter),
+	}
+}
+
+func (r *gceRateLimiter) limiterFor(project, op string) *rate.Limiter {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := rateLimiterKey{project: project, op: op}
+	if limiter, found := r.limiters[key]; found {
+		return limiter
+	}
+	cfg, found := r.configs[op]
+	if !found {
+		cfg = RateLimiterConfig{QPS: 10, Burst: 20}
+	}
+	limiter := rate.NewLimiter(rate.Limit(cfg.QPS), cfg.Burst)
+	r.limiters[key] = limiter
+	return limiter
+}
+
+// Accept reserves the next available slot for op/project.
+func (r *gceRateLimiter) Accept(project string, op string) *rate.Reservation {
+	return r.limiterFor(project, op).Reserve()
+}
+
+// Wait blocks until res may proceed, or ctx is done.
+func (r *gceRateLimiter) Wait(ctx context.Context, res *rate.Reservation) error {
+	delay := res.Delay()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		res.Cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+	gce "google.golang.org/api/compute/v1"
+	iamcredentials "google.golang.org/api/iamcredentials/v1"
+	provider_gce "k8s.io/kubernetes/pkg/cloudprovider/providers/gce"
+)
+
+// ServiceFactory mints a *gce.Service able to act against project. Implementations
+// must be safe for concurrent use. GceManager caches the first service NewService
+// returns without error for a project and doesn't call it for that project again;
+// after an error it calls NewService again on the next use of that project.
+type ServiceFactory interface {
+	// NewService returns a *gce.Service authorized to act against project.
+	NewService(project string) (*gce.Service, error)
+}
+
+type projectCredentials struct {
+	serviceAccount string
+	tokenURL       string
+	tokenBody      string
+}
+
+// defaultServiceFactory builds a *gce.Service per project, using defaultTokenSource
+// unless overridden by a [Project "<id>"] section in the cloud config.
+type defaultServiceFactory struct {
+	defaultTokenSource oauth2.TokenSource
+	projects           map[string]projectCredentials
+}
+
+// newServiceFactory builds a ServiceFactory from cfg's [Project "..."] sections.
+func newServiceFactory(defaultTokenSource oauth2.TokenSource, cfg *cloudConfig) ServiceFactory {
+	projects := make(map[string]projectCredentials, len(cfg.Project))
+	for project, section := range cfg.Project {
+		projects[project] = projectCredentials{
+			serviceAccount: section.ServiceAccount,
+			tokenURL:       section.TokenURL,
+			tokenBody:      section.TokenBody,
+		}
+	}
+	return &defaultServiceFactory{defaultTokenSource: defaultTokenSource, projects: projects}
+}
+
+// NewService returns a *gce.Service authorized to act against project.
+func (f *defaultServiceFactory) NewService(project string) (*gce.Service, error) {
+	tokenSource := f.defaultTokenSource
+	if creds, found := f.projects[project]; found {
+		switch {
+		case creds.serviceAccount != "":
+			ts, err := impersonatedTokenSource(f.defaultTokenSource, creds.serviceAccount)
+			if err != nil {
+				return nil, err
+			}
+			tokenSource = ts
+		case creds.tokenURL != "":
+			tokenSource = provider_gce.NewAltTokenSource(creds.tokenURL, creds.tokenBody)
+		}
+	}
+	client := oauth2.NewClient(oauth2.NoContext, tokenSource)
+	return gce.New(client)
+}
+
+// impersonatedTokenSource returns an oauth2.TokenSource that mints short-lived
+// access tokens for serviceAccount by impersonating it through the IAM
+// Credentials API, authenticated as callerTokenSource.
+func impersonatedTokenSource(callerTokenSource oauth2.TokenSource, serviceAccount string) (oauth2.TokenSource, error) {
+	client := oauth2.NewClient(oauth2.NoContext, callerTokenSource)
+	iamService, err := iamcredentials.New(client)
+	if err != nil {
+		return nil, err
+	}
+	return oauth2.ReuseTokenSource(nil, &impersonatedTokenFetcher{
+		service: iamService,
+		name:    fmt.Sprintf("projects/-/serviceAccounts/%s", serviceAccount),
+	}), nil
+}
+
+// impersonatedTokenFetcher implements oauth2.TokenSource by calling
+// GenerateAccessToken on each refresh.
+type impersonatedTokenFetcher struct {
+	service *iamcredentials.Service
+	name    string
+}
+
+func (f *impersonatedTokenFetcher) Token() (*oauth2.Token, error) {
+	resp, err := f.service.Projects.ServiceAccounts.GenerateAccessToken(f.name, &iamcredentials.GenerateAccessTokenRequest{
+		Scope: []string{gce.ComputeScope},
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	expiry, err := time.Parse(time.RFC3339, resp.ExpireTime)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: resp.AccessToken, Expiry: expiry}, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	gce "google.golang.org/api/compute/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// templateBuilder builds sample nodes from GCE instance templates.
+type templateBuilder struct {
+	gceManager *GceManager
+}
+
+// getMigTemplate fetches the instance template backing the given mig.
+func (t *templateBuilder) getMigTemplate(mig *Mig) (*gce.InstanceTemplate, error) {
+	service, err := t.gceManager.serviceFor(mig.Project)
+	if err != nil {
+		return nil, err
+	}
+	var instanceTemplate string
+	if mig.IsRegional() {
+		igm, err := service.RegionInstanceGroupManagers.Get(mig.Project, mig.Region, mig.Name).Do()
+		if err != nil {
+			return nil, err
+		}
+		instanceTemplate = igm.InstanceTemplate
+	} else {
+		igm, err := service.InstanceGroupManagers.Get(mig.Project, mig.Zone, mig.Name).Do()
+		if err != nil {
+			return nil, err
+		}
+		instanceTemplate = igm.InstanceTemplate
+	}
+	templateUrl, err := url.Parse(instanceTemplate)
+	if err != nil {
+		return nil, err
+	}
+	_, templateName := path.Split(templateUrl.Path)
+	return service.InstanceTemplates.Get(mig.Project, templateName).Do()
+}
+
+// buildNodeFromTemplate builds a sample node that would be created by the given instance template.
+// It is used to estimate the shape of nodes that a mig would produce before it is scaled up.
+func (t *templateBuilder) buildNodeFromTemplate(mig *Mig, template *gce.InstanceTemplate) (*apiv1.Node, error) {
+	if template.Properties == nil {
+		return nil, fmt.Errorf("instance template %s has no properties", template.Name)
+	}
+
+	cpu, mem, err := machineTypeResources(template.Properties.MachineType)
+	if err != nil {
+		return nil, err
+	}
+
+	node := apiv1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s-template", mig.Name),
+			Labels: map[string]string{},
+		},
+		Status: apiv1.NodeStatus{
+			Capacity: apiv1.ResourceList{
+				apiv1.ResourcePods:   *resource.NewQuantity(110, resource.DecimalSI),
+				apiv1.ResourceCPU:    *resource.NewQuantity(cpu, resource.DecimalSI),
+				apiv1.ResourceMemory: *resource.NewQuantity(mem, resource.DecimalSI),
+			},
+		},
+	}
+	node.Status.Allocatable = node.Status.Capacity
+	node.Status.Conditions = []apiv1.NodeCondition{
+		{
+			Type:               apiv1.NodeReady,
+			Status:             apiv1.ConditionTrue,
+			LastTransitionTime: metav1.Time{Time: time.Unix(0, 0)},
+		},
+	}
+	return &node, nil
+}
+
+// predefinedMachineTypeMemMbPerCpu gives the memory-per-vCPU ratio, in MB, for
+// each predefined machine type family, per https://cloud.google.com/compute/docs/machine-types.
+var predefinedMachineTypeMemMbPerCpu = map[string]int64{
+	"n1-standard": 3840,
+	"n1-highmem":  6656,
+	"n1-highcpu":  921,
+}
+
+// fixedSizeMachineTypes gives the (cpu, memory MB) capacity of machine types
+// that don't follow the "<family>-<cpu count>" naming pattern.
+var fixedSizeMachineTypes = map[string][2]int64{
+	"f1-micro": {1, 614},
+	"g1-small": {1, 1740},
+}
+
+// machineTypeResources returns an approximate (cpu, memory bytes) capacity for the given
+// machine type, based on its name (e.g. "n1-standard-4", "custom-4-16384").
+func machineTypeResources(machineType string) (cpu int64, memBytes int64, err error) {
+	if size, found := fixedSizeMachineTypes[machineType]; found {
+		return size[0], size[1] * 1024 * 1024, nil
+	}
+
+	parts := strings.Split(machineType, "-")
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("unrecognized machine type: %s", machineType)
+	}
+
+	if parts[0] == "custom" {
+		return customMachineTypeResources(parts)
+	}
+
+	family := strings.Join(parts[:len(parts)-1], "-")
+	memMbPerCpu, found := predefinedMachineTypeMemMbPerCpu[family]
+	if !found {
+		return 0, 0, fmt.Errorf("unsupported machine type: %s", machineType)
+	}
+	cpu, err = strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cpu, cpu * memMbPerCpu * 1024 * 1024, nil
+}
+
+// customMachineTypeResources parses the cpu and memory (in MB) out of a
+// "custom-<cpu>-<mem-mb>" machine type, already split on "-".
+func customMachineTypeResources(parts []string) (cpu int64, memBytes int64, err error) {
+	if len(parts) != 3 {
+		return 0, 0, fmt.Errorf("unsupported machine type: custom-%s", strings.Join(parts[1:], "-"))
+	}
+
+	cpu, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	memMb, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cpu, memMb * 1024 * 1024, nil
+}
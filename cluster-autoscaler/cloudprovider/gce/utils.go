@@ -0,0 +1,87 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	minAutoprovisionedSize = 0
+	maxAutoprovisionedSize = 1000
+)
+
+// zoneToRegion returns the region a zone belongs to, e.g. "us-central1-b" -> "us-central1".
+func zoneToRegion(zone string) string {
+	idx := strings.LastIndex(zone, "-")
+	if idx < 0 {
+		return zone
+	}
+	return zone[:idx]
+}
+
+// GenerateInstanceUrl generates url for instance.
+func GenerateInstanceUrl(project, zone, name string) string {
+	return fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s/instances/%s", project, zone, name)
+}
+
+// ParseInstanceUrl expects url in format:
+// https://www.googleapis.com/compute/v1/projects/<project-id>/zones/<zone>/instances/<name>
+func ParseInstanceUrl(url string) (project string, zone string, name string, err error) {
+	project, location, regional, name, err := parseGceUrl(url, "instances")
+	if err != nil {
+		return "", "", "", err
+	}
+	if regional {
+		return "", "", "", fmt.Errorf("unexpected regional instance url: %s", url)
+	}
+	return project, location, name, nil
+}
+
+// parseGceUrl expects url in one of the two formats:
+// https://www.googleapis.com/compute/v1/projects/<project-id>/zones/<zone>/<resource>/<name>
+// https://www.googleapis.com/compute/v1/projects/<project-id>/regions/<region>/<resource>/<name>
+// and returns the project, the zone or region (location), whether the url was
+// regional, and the resource name.
+func parseGceUrl(url, resource string) (project string, location string, regional bool, name string, err error) {
+	errMsg := fmt.Errorf("wrong url: expected format https://www.googleapis.com/compute/v1/projects/<project-id>/{zones/<zone>|regions/<region>}/%s/<name>, got %s", resource, url)
+	url = strings.TrimPrefix(url, "https://")
+	url = strings.TrimPrefix(url, "http://")
+	splitted := strings.Split(url, "/")
+	if len(splitted) != 9 {
+		return "", "", false, "", errMsg
+	}
+	if splitted[0] != "www.googleapis.com" || splitted[1] != "compute" {
+		return "", "", false, "", errMsg
+	}
+	if splitted[3] != "projects" || splitted[7] != resource {
+		return "", "", false, "", errMsg
+	}
+	switch splitted[5] {
+	case "zones":
+		regional = false
+	case "regions":
+		regional = true
+	default:
+		return "", "", false, "", errMsg
+	}
+	project = splitted[4]
+	location = splitted[6]
+	name = splitted[8]
+	return project, location, regional, name, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2016 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gce
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGceUrl(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		url         string
+		resource    string
+		wantProject string
+		wantLoc     string
+		wantRegion  bool
+		wantName    string
+		wantErr     bool
+	}{
+		{
+			desc:        "zonal url",
+			url:         "https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b/instances/inst-1",
+			resource:    "instances",
+			wantProject: "proj",
+			wantLoc:     "us-central1-b",
+			wantRegion:  false,
+			wantName:    "inst-1",
+		},
+		{
+			desc:        "regional url",
+			url:         "https://www.googleapis.com/compute/v1/projects/proj/regions/us-central1/instanceGroupManagers/mig-1",
+			resource:    "instanceGroupManagers",
+			wantProject: "proj",
+			wantLoc:     "us-central1",
+			wantRegion:  true,
+			wantName:    "mig-1",
+		},
+		{
+			desc:     "wrong resource",
+			url:      "https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b/instances/inst-1",
+			resource: "instanceGroupManagers",
+			wantErr:  true,
+		},
+		{
+			desc:     "neither zones nor regions",
+			url:      "https://www.googleapis.com/compute/v1/projects/proj/networks/default/instances/inst-1",
+			resource: "instances",
+			wantErr:  true,
+		},
+		{
+			desc:     "too few segments",
+			url:      "https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b",
+			resource: "instances",
+			wantErr:  true,
+		},
+		{
+			desc:     "wrong host",
+			url:      "https://example.com/compute/v1/projects/proj/zones/us-central1-b/instances/inst-1",
+			resource: "instances",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			project, loc, regional, name, err := parseGceUrl(tc.url, tc.resource)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantProject, project)
+			assert.Equal(t, tc.wantLoc, loc)
+			assert.Equal(t, tc.wantRegion, regional)
+			assert.Equal(t, tc.wantName, name)
+		})
+	}
+}
+
+func TestParseInstanceUrl(t *testing.T) {
+	project, zone, name, err := ParseInstanceUrl(
+		"https://www.googleapis.com/compute/v1/projects/proj/zones/us-central1-b/instances/inst-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "proj", project)
+	assert.Equal(t, "us-central1-b", zone)
+	assert.Equal(t, "inst-1", name)
+
+	_, _, _, err = ParseInstanceUrl(
+		"https://www.googleapis.com/compute/v1/projects/proj/regions/us-central1/instances/inst-1")
+	assert.Error(t, err, "regional instance urls don't exist and should be rejected")
+}